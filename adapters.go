@@ -0,0 +1,157 @@
+package madhatter
+
+import (
+	"net/http"
+
+	"context"
+)
+
+// Instance is implemented by the wrapper types for third-party middleware
+// signatures (NegroniHandlerFunc, AliceConstructor, ChiMiddlewareFunc,
+// MartiniHandlerFunc, ...) so that AdaptInstance can turn any of them into a
+// Constructor without madhatter having to know about each ecosystem's
+// Constructor-equivalent by name.
+type Instance interface {
+	adapt() Constructor
+}
+
+// AdaptInstance turns i into a Constructor, letting users drop middleware
+// written for other ecosystems into a Chain unchanged.
+func AdaptInstance(i Instance) Constructor {
+	return i.adapt()
+}
+
+// InstanceE is the error-returning counterpart to Instance, for upstream
+// middleware signatures that can themselves fail at build time (e.g. the
+// containous/alice fork).
+type InstanceE interface {
+	adaptE() ConstructorE
+}
+
+// AdaptInstanceE turns i into a ConstructorE, for use with NewE/AppendE.
+func AdaptInstanceE(i InstanceE) ConstructorE {
+	return i.adaptE()
+}
+
+// seedRequest returns r carrying ctx as its context.Context, the seam
+// through which every adapter in this file hands ctx to middleware written
+// against the stdlib http.Handler signature.
+func seedRequest(ctx context.Context, r *http.Request) *http.Request {
+	return withContext(ctx, r)
+}
+
+// NegroniHandlerFunc adapts a urfave/negroni-style middleware function --
+// func(http.ResponseWriter, *http.Request, http.HandlerFunc) -- into a
+// Constructor via AdaptInstance.
+//
+// The request passed to f is seeded with the incoming context.Context. When
+// f calls next, whatever context.Context that request carries -- unchanged,
+// or replaced by f via r.WithContext to stuff a value for downstream
+// handlers -- is the one forwarded to the next madhatter.Handler, so
+// middleware that mutates the request's context is not silently undone.
+type NegroniHandlerFunc func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc)
+
+func (f NegroniHandlerFunc) adapt() Constructor {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+			f(w, seedRequest(ctx, r), func(w2 http.ResponseWriter, r2 *http.Request) {
+				next.ServeHTTP(r2.Context(), w2, r2)
+			})
+		})
+	}
+}
+
+// AliceConstructor adapts a justinas/alice-style middleware function --
+// func(http.Handler) http.Handler -- into a Constructor via AdaptInstance.
+type AliceConstructor func(http.Handler) http.Handler
+
+func (f AliceConstructor) adapt() Constructor {
+	return adaptHTTPMiddleware(f)
+}
+
+// ChiMiddlewareFunc adapts a go-chi/chi middleware.Middleware -- also
+// func(http.Handler) http.Handler, but declared separately so call sites
+// read as "this came from chi" -- into a Constructor via AdaptInstance.
+type ChiMiddlewareFunc func(http.Handler) http.Handler
+
+func (f ChiMiddlewareFunc) adapt() Constructor {
+	return adaptHTTPMiddleware(f)
+}
+
+// adaptHTTPMiddleware is the shared implementation behind AliceConstructor
+// and ChiMiddlewareFunc: both wrap a plain func(http.Handler) http.Handler.
+// f is called once, at chain-build time, like any other Constructor --
+// matching real alice/chi middleware that does one-time setup in that outer
+// closure (e.g. chi/middleware.Throttle allocating its semaphore there).
+// See NegroniHandlerFunc for how context.Context is carried forward.
+func adaptHTTPMiddleware(f func(http.Handler) http.Handler) Constructor {
+	return func(next Handler) Handler {
+		wrapped := f(http.HandlerFunc(func(w2 http.ResponseWriter, r2 *http.Request) {
+			next.ServeHTTP(r2.Context(), w2, r2)
+		}))
+
+		return HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+			wrapped.ServeHTTP(w, seedRequest(ctx, r))
+		})
+	}
+}
+
+// AliceConstructorE adapts a containous/alice-fork-style middleware function
+// -- func(http.Handler) (http.Handler, error) -- into a ConstructorE via
+// AdaptInstanceE, so a middleware that fails to build (bad config, a
+// template that doesn't parse) surfaces its error through ThenE rather than
+// panicking. Like AliceConstructor and ChiMiddlewareFunc, f itself is called
+// once per chain build -- when ThenE invokes the returned ConstructorE while
+// walking the chain, as it does any plain ConstructorE -- rather than once
+// per request.
+type AliceConstructorE func(http.Handler) (http.Handler, error)
+
+func (f AliceConstructorE) adaptE() ConstructorE {
+	return func(next Handler) (Handler, error) {
+		wrapped, err := f(http.HandlerFunc(func(w2 http.ResponseWriter, r2 *http.Request) {
+			next.ServeHTTP(r2.Context(), w2, r2)
+		}))
+		if err != nil {
+			return nil, err
+		}
+
+		return HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+			wrapped.ServeHTTP(w, seedRequest(ctx, r))
+		}), nil
+	}
+}
+
+// MartiniContext provides the subset of go-martini/martini's Context used by
+// martini-style middleware adapted via AdaptInstance: continuing the chain.
+// It is declared locally so adapting martini-style middleware does not pull
+// in the martini package itself.
+type MartiniContext interface {
+	Next()
+}
+
+// MartiniHandlerFunc adapts a go-martini/martini-style middleware function --
+// func(http.ResponseWriter, *http.Request, martini.Context) -- into a
+// Constructor via AdaptInstance. Martini's Context, unlike the http.Handler
+// signatures above, has no request of its own to replace, so Next() simply
+// resumes the chain with the context.Context it was entered with.
+type MartiniHandlerFunc func(w http.ResponseWriter, r *http.Request, c MartiniContext)
+
+type martiniContext struct {
+	ctx  context.Context
+	next Handler
+	w    http.ResponseWriter
+	r    *http.Request
+}
+
+func (c *martiniContext) Next() {
+	c.next.ServeHTTP(c.ctx, c.w, c.r)
+}
+
+func (f MartiniHandlerFunc) adapt() Constructor {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+			r = seedRequest(ctx, r)
+			f(w, r, &martiniContext{ctx: ctx, next: next, w: w, r: r})
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package madhatter
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"context"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliceConstructorAdapterOrdersHandlersRight(t *testing.T) {
+	alice := AliceConstructor(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("t1\n"))
+			h.ServeHTTP(w, r)
+		})
+	})
+
+	chain := New(AdaptInstance(alice), tagMiddleware("t2\n"))
+	chained := chain.ThenFunc(testApp)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chained.ServeHTTP(w, r)
+	assert.Equal(t, "t1\nt2\napp\n", w.Body.String())
+}
+
+func TestAliceConstructorAdapterForwardsReplacedContext(t *testing.T) {
+	type key string
+	alice := AliceConstructor(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(context.WithValue(r.Context(), key("from"), "alice"))
+			h.ServeHTTP(w, r)
+		})
+	})
+
+	var seen string
+	capture := HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		seen, _ = ctx.Value(key("from")).(string)
+	})
+
+	chain := New(AdaptInstance(alice))
+	chained := chain.Then(capture)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chained.ServeHTTP(w, r)
+	assert.Equal(t, "alice", seen)
+}
+
+func TestAliceConstructorAdapterCallsFactoryOnceAtBuildTime(t *testing.T) {
+	var calls int
+	alice := AliceConstructor(func(h http.Handler) http.Handler {
+		calls++
+		return h
+	})
+
+	chained := New(AdaptInstance(alice)).ThenFunc(testApp)
+	assert.Equal(t, 1, calls)
+
+	for i := 0; i < 5; i++ {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest("GET", "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chained.ServeHTTP(w, r)
+	}
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestChiMiddlewareFuncAdapter(t *testing.T) {
+	chi := ChiMiddlewareFunc(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("chi\n"))
+			h.ServeHTTP(w, r)
+		})
+	})
+
+	chained := New(AdaptInstance(chi)).ThenFunc(testApp)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chained.ServeHTTP(w, r)
+	assert.Equal(t, "chi\napp\n", w.Body.String())
+}
+
+func TestAliceConstructorEAdapterSurfacesBuildError(t *testing.T) {
+	buildErr := errors.New("bad template")
+	alice := AliceConstructorE(func(h http.Handler) (http.Handler, error) {
+		return nil, buildErr
+	})
+
+	chain := NewE(AdaptInstanceE(alice))
+	_, err := chain.ThenE(testApp)
+
+	assert.Equal(t, buildErr, err)
+}
+
+func TestMartiniHandlerFuncAdapter(t *testing.T) {
+	martini := MartiniHandlerFunc(func(w http.ResponseWriter, r *http.Request, c MartiniContext) {
+		w.Write([]byte("martini\n"))
+		c.Next()
+	})
+
+	chained := New(AdaptInstance(martini)).ThenFunc(testApp)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chained.ServeHTTP(w, r)
+	assert.Equal(t, "martini\napp\n", w.Body.String())
+}
+
+func TestMartiniHandlerFuncAdapterForwardsIncomingContext(t *testing.T) {
+	type key string
+	upstream := func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(context.WithValue(ctx, key("from"), "upstream"), w, r)
+		})
+	}
+
+	var seen string
+	martini := MartiniHandlerFunc(func(w http.ResponseWriter, r *http.Request, c MartiniContext) {
+		seen, _ = r.Context().Value(key("from")).(string)
+		c.Next()
+	})
+
+	chained := New(upstream, AdaptInstance(martini)).ThenFunc(testApp)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chained.ServeHTTP(w, r)
+	assert.Equal(t, "upstream", seen)
+}
@@ -0,0 +1,129 @@
+package madhatter
+
+import (
+	"fmt"
+	"net/http"
+
+	"context"
+)
+
+// HandlerE is a Handler variant whose ServeHTTP is allowed to fail. It lets a
+// handler `return err` instead of writing an error response itself, leaving
+// the translation into an HTTP response to the chain's ErrorHandler.
+type HandlerE interface {
+	ServeHTTP(context.Context, http.ResponseWriter, *http.Request) error
+}
+
+// HandlerFuncE is an adapter to allow the use of ordinary functions as
+// HandlerE.
+type HandlerFuncE func(context.Context, http.ResponseWriter, *http.Request) error
+
+// ServeHTTP calls f(ctx, w, r).
+func (f HandlerFuncE) ServeHTTP(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return f(ctx, w, r)
+}
+
+// ErrorHandler translates an error returned from a HandlerE into an HTTP
+// response. It is installed on a Chain via OnError.
+type ErrorHandler func(ctx context.Context, w http.ResponseWriter, r *http.Request, err error)
+
+// defaultErrorHandler is used by ThenE when no OnError handler was
+// installed, so a HandlerE's error is still turned into a response instead
+// of being silently dropped.
+func defaultErrorHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) {
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// A ConstructorE is the error-returning counterpart to Constructor: it lets a
+// middleware factory fail at build time (bad configuration, a template that
+// doesn't parse, ...) instead of panicking.
+type ConstructorE func(Handler) (Handler, error)
+
+// NewE creates a new Chain from a list of ConstructorE, memorizing them in
+// order. As with New, constructors are not invoked until ThenE() is called.
+func NewE(constructors ...ConstructorE) Chain {
+	c := Chain{
+		finalize: createRootHandler,
+	}
+	c.constructorsE = append(c.constructorsE, constructors...)
+	return c
+}
+
+// AppendE extends a Chain, adding the specified error-returning constructors
+// at the end of the Chain. AppendE returns a new chain, leaving the original
+// one untouched.
+func (c Chain) AppendE(constructors ...ConstructorE) Chain {
+	newConstructorsE := make([]ConstructorE, len(c.constructorsE)+len(constructors))
+	copy(newConstructorsE, c.constructorsE)
+	copy(newConstructorsE[len(c.constructorsE):], constructors)
+
+	newChain := c
+	newChain.constructorsE = newConstructorsE
+	return newChain
+}
+
+// OnError installs handler as the Chain's error responder. ThenE() consults
+// it whenever the final Handler implements HandlerE and its ServeHTTP
+// returns a non-nil error. OnError returns a new chain, leaving the original
+// one untouched.
+func (c Chain) OnError(handler ErrorHandler) Chain {
+	newChain := c
+	newChain.onError = handler
+	return newChain
+}
+
+// ThenE chains the middleware -- both the constructors added via New/Append
+// and the error-returning ones added via NewE/AppendE -- and returns the
+// final http.Handler.
+//
+// Following the containous/alice fork pattern, ThenE short-circuits on the
+// first constructor error and surfaces it to the caller instead of building
+// a chain around a broken middleware.
+//
+// h may be either a Handler or a HandlerE (the two are mutually exclusive
+// ServeHTTP signatures, so no single type can implement both). If h is a
+// HandlerE, errors returned from its ServeHTTP are caught and translated
+// into an HTTP response rather than being silently dropped: by the
+// Chain-level ErrorHandler installed via OnError, or, when none was
+// installed, by a default that writes a 500 carrying the error text.
+func (c Chain) ThenE(h interface{}) (http.Handler, error) {
+	var final Handler
+	switch t := h.(type) {
+	case nil:
+		final = adaptFinal(http.DefaultServeMux)
+	case HandlerE:
+		if c.onError != nil {
+			final = wrapHandlerE(t, c.onError)
+		} else {
+			final = wrapHandlerE(t, defaultErrorHandler)
+		}
+	case Handler:
+		final = t
+	default:
+		return nil, fmt.Errorf("madhatter: ThenE: %T implements neither Handler nor HandlerE", h)
+	}
+
+	for i := len(c.constructorsE) - 1; i >= 0; i-- {
+		var err error
+		final, err = c.constructorsE[i](final)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := len(c.constructors) - 1; i >= 0; i-- {
+		final = c.constructors[i](final)
+	}
+
+	return c.finalize(final), nil
+}
+
+// wrapHandlerE adapts a HandlerE into a Handler, routing any error it
+// returns through onError instead of letting it propagate unhandled.
+func wrapHandlerE(h HandlerE, onError ErrorHandler) Handler {
+	return HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		if err := h.ServeHTTP(ctx, w, r); err != nil {
+			onError(ctx, w, r, err)
+		}
+	})
+}
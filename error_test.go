@@ -0,0 +1,70 @@
+package madhatter
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"context"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThenEWorksWithNoMiddleware(t *testing.T) {
+	chain := NewE()
+	final, err := chain.ThenE(testApp)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, final)
+
+	w := httptest.NewRecorder()
+	r, reqErr := http.NewRequest("GET", "/", nil)
+	if reqErr != nil {
+		t.Fatal(reqErr)
+	}
+
+	final.ServeHTTP(w, r)
+	assert.Equal(t, w.Body.String(), "app\n")
+}
+
+func TestThenEShortCircuitsOnConstructorError(t *testing.T) {
+	failErr := errors.New("bad config")
+	failing := func(h Handler) (Handler, error) {
+		return nil, failErr
+	}
+
+	chain := NewE(failing)
+	final, err := chain.ThenE(testApp)
+
+	assert.Nil(t, final)
+	assert.Equal(t, failErr, err)
+}
+
+func TestOnErrorCatchesHandlerEErrors(t *testing.T) {
+	wantErr := errors.New("boom")
+
+	var caught error
+	chain := NewE().OnError(func(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) {
+		caught = err
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	failingApp := HandlerFuncE(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return wantErr
+	})
+
+	final, err := chain.ThenE(failingApp)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r, reqErr := http.NewRequest("GET", "/", nil)
+	if reqErr != nil {
+		t.Fatal(reqErr)
+	}
+
+	final.ServeHTTP(w, r)
+
+	assert.Equal(t, wantErr, caught)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
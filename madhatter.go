@@ -3,7 +3,7 @@ package madhatter
 import (
 	"net/http"
 
-	"golang.org/x/net/context"
+	"context"
 )
 
 // Handler provides an interface similar to http.Handler but supporting
@@ -28,7 +28,9 @@ type Constructor func(Handler) Handler
 // Chain is effectively immutable: once created, it will always hold
 // the same set of constructors in the same order.
 type Chain struct {
-	constructors []Constructor
+	constructors  []Constructor
+	constructorsE []ConstructorE
+	onError       ErrorHandler
 
 	finalize func(Handler) http.Handler
 }
@@ -90,10 +92,34 @@ func (c Chain) Append(constructors ...Constructor) Chain {
 	copy(newCons, c.constructors)
 	copy(newCons[len(c.constructors):], constructors)
 
-	newChain := New(newCons...)
+	newChain := c
+	newChain.constructors = newCons
 	return newChain
 }
 
+// ThenStd is like Then, but takes a stdlib http.Handler as the final handler
+// instead of a madhatter.Handler. The chain's context.Context is attached to
+// the *http.Request via WithContext before h runs, so h can recover it with
+// FromContext (or r.Context() directly) instead of needing the
+// madhatter.Handler signature.
+//
+// ThenStd treats nil as http.DefaultServeMux, like Then.
+func (c Chain) ThenStd(h http.Handler) http.Handler {
+	if h == nil {
+		return c.Then(nil)
+	}
+
+	return c.Then(adaptFinal(h))
+}
+
+// FromContext is a compatibility shim for handlers migrating off
+// madhatter.Handler: it recovers the context.Context the chain attached to r
+// (for a ThenStd handler, the one the last middleware passed on), equivalent
+// to calling r.Context() directly.
+func FromContext(r *http.Request) context.Context {
+	return r.Context()
+}
+
 func createRootHandler(h Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var (
@@ -104,12 +130,23 @@ func createRootHandler(h Handler) http.Handler {
 		ctx, cancel = context.WithCancel(context.Background())
 		defer cancel() // cancel context as soon as the request returns
 
-		h.ServeHTTP(ctx, w, r)
+		h.ServeHTTP(ctx, w, withContext(ctx, r))
 	})
 }
 
+// withContext returns r carrying ctx as its context.Context, or r unchanged
+// if r is nil -- (*http.Request).WithContext dereferences its receiver, so
+// every call site that might see a nil r (a chain served as ServeHTTP(w, nil))
+// goes through here instead of calling it directly.
+func withContext(ctx context.Context, r *http.Request) *http.Request {
+	if r == nil {
+		return nil
+	}
+	return r.WithContext(ctx)
+}
+
 func adaptFinal(h http.Handler) Handler {
-	return HandlerFunc(func(_ context.Context, w http.ResponseWriter, r *http.Request) {
-		h.ServeHTTP(w, r)
+	return HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, withContext(ctx, r))
 	})
 }
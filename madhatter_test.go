@@ -163,3 +163,52 @@ func TestNegroniAdapter(t *testing.T) {
 
 	assert.Equal(t, w.Body.String(), "t1\nt2\napp\n")
 }
+
+func TestThenStdRunsPlainHTTPHandlerBehindTheChain(t *testing.T) {
+	var sawCtx context.Context
+	stdApp := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawCtx = FromContext(r)
+		w.Write([]byte("std\n"))
+	})
+
+	chained := New(tagMiddleware("t1\n")).ThenStd(stdApp)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chained.ServeHTTP(w, r)
+
+	assert.Equal(t, w.Body.String(), "std\n")
+	assert.NotNil(t, sawCtx)
+}
+
+func TestThenStdWorksWithNilRequest(t *testing.T) {
+	stdApp := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("std\n"))
+	})
+
+	assert.NotPanics(t, func() {
+		chained := New().ThenStd(stdApp)
+
+		w := httptest.NewRecorder()
+		chained.ServeHTTP(w, nil)
+
+		assert.Equal(t, w.Body.String(), "std\n")
+	})
+}
+
+func TestThenStdTreatsNilAsDefaultServeMux(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chained := New().ThenStd(nil)
+	chained.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"context"
+
+	"github.com/docbobo/madhatter"
+)
+
+// compressWriter wraps an http.ResponseWriter, transparently compressing
+// the body through w once the handler's Content-Type is known to be on the
+// allowlist.
+type compressWriter struct {
+	http.ResponseWriter
+	compressor io.WriteCloser
+	encoding   string
+	types      []string
+	allowed    bool
+	decided    bool
+}
+
+func (cw *compressWriter) decide() {
+	if cw.decided {
+		return
+	}
+	cw.decided = true
+
+	ct := cw.Header().Get("Content-Type")
+	cw.allowed = len(cw.types) == 0 || matchesType(ct, cw.types)
+	if cw.allowed {
+		cw.Header().Set("Content-Encoding", cw.encoding)
+		cw.Header().Del("Content-Length")
+	}
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	cw.decide()
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	cw.decide()
+	if !cw.allowed {
+		return cw.ResponseWriter.Write(b)
+	}
+	return cw.compressor.Write(b)
+}
+
+func (cw *compressWriter) Close() error {
+	if cw.compressor != nil && cw.allowed {
+		return cw.compressor.Close()
+	}
+	return nil
+}
+
+func matchesType(contentType string, types []string) bool {
+	contentType = strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	for _, t := range types {
+		if strings.EqualFold(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Compress returns a madhatter.Constructor that gzip- or deflate-compresses
+// responses, negotiated against the request's Accept-Encoding header (gzip
+// is preferred over deflate when the client accepts both). level is a
+// compress/gzip compression level (e.g. gzip.DefaultCompression). When types
+// is non-empty, only responses whose Content-Type matches one of them are
+// compressed; an empty types compresses everything.
+func Compress(level int, types ...string) madhatter.Constructor {
+	return func(next madhatter.Handler) madhatter.Handler {
+		return madhatter.HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+			accept := r.Header.Get("Accept-Encoding")
+
+			var (
+				compressor io.WriteCloser
+				encoding   string
+			)
+			switch {
+			case strings.Contains(accept, "gzip"):
+				gz, err := gzip.NewWriterLevel(w, level)
+				if err != nil {
+					next.ServeHTTP(ctx, w, r)
+					return
+				}
+				compressor, encoding = gz, "gzip"
+			case strings.Contains(accept, "deflate"):
+				fl, err := flate.NewWriter(w, level)
+				if err != nil {
+					next.ServeHTTP(ctx, w, r)
+					return
+				}
+				compressor, encoding = fl, "deflate"
+			default:
+				next.ServeHTTP(ctx, w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				compressor:     compressor,
+				encoding:       encoding,
+				types:          types,
+			}
+			defer cw.Close()
+
+			next.ServeHTTP(ctx, cw, r)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package middleware provides a set of ready-made madhatter.Constructors
+// modeled on go-chi/middleware and echo: Recoverer, Logger, Timeout,
+// Compress, RequestID and RealIP. Each is context-aware -- it operates on
+// madhatter.Handler rather than plain http.Handler -- so it can be dropped
+// straight into a madhatter.Chain.
+package middleware
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"context"
+
+	"github.com/docbobo/madhatter"
+)
+
+// Logger is a madhatter.Constructor that logs one line per request, in the
+// form "METHOD path status bytes duration". It wraps the http.ResponseWriter
+// to capture the status code and byte count the handler actually wrote.
+func Logger(next madhatter.Handler) madhatter.Handler {
+	return madhatter.HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		sw := wrapWriter(w)
+		start := time.Now()
+
+		next.ServeHTTP(ctx, sw, r)
+
+		log.Printf("%s %s %d %d %s", r.Method, r.URL.Path, sw.status, sw.bytes, time.Since(start))
+	})
+}
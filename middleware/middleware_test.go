@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"context"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docbobo/madhatter"
+)
+
+var okApp = madhatter.HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok"))
+})
+
+func TestRecovererConvertsPanicToInternalServerError(t *testing.T) {
+	panicky := madhatter.HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	h := Recoverer(panicky)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.ServeHTTP(context.Background(), w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestRecovererWithHandlerReceivesPanicValueAndStack(t *testing.T) {
+	panicky := madhatter.HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	var gotRec interface{}
+	var gotStack []byte
+	h := RecovererWithHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, rec interface{}, stack []byte) {
+		gotRec = rec
+		gotStack = stack
+		w.WriteHeader(http.StatusTeapot)
+	})(panicky)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.ServeHTTP(context.Background(), w, r)
+
+	assert.Equal(t, "boom", gotRec)
+	assert.NotEmpty(t, gotStack)
+	assert.Equal(t, http.StatusTeapot, w.Code)
+}
+
+func TestTimeoutCancelsContextAfterDuration(t *testing.T) {
+	var sawDone bool
+	slow := madhatter.HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		<-ctx.Done()
+		sawDone = true
+	})
+
+	h := Timeout(time.Millisecond)(slow)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.ServeHTTP(context.Background(), w, r)
+
+	assert.True(t, sawDone)
+}
+
+func TestRequestIDGeneratesAndEchoesHeader(t *testing.T) {
+	var seen string
+	capture := madhatter.HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(ctx)
+	})
+
+	h := RequestID(capture)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.ServeHTTP(context.Background(), w, r)
+
+	assert.NotEmpty(t, seen)
+	assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+}
+
+func TestRealIPPrefersForwardedForOverRealIP(t *testing.T) {
+	var seen string
+	capture := madhatter.HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		seen = r.RemoteAddr
+	})
+
+	h := RealIP(capture)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("X-Forwarded-For", "203.0.113.9, 10.0.0.1")
+	r.Header.Set("X-Real-IP", "10.0.0.2")
+
+	h.ServeHTTP(context.Background(), w, r)
+
+	assert.Equal(t, "203.0.113.9", seen)
+}
+
+func TestCompressNegotiatesGzip(t *testing.T) {
+	h := Compress(1)(okApp)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Accept-Encoding", "gzip")
+
+	h.ServeHTTP(context.Background(), w, r)
+
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+}
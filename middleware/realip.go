@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"context"
+
+	"github.com/docbobo/madhatter"
+)
+
+// RealIP is a madhatter.Constructor that overwrites r.RemoteAddr with the
+// client address reported by X-Forwarded-For (its first, left-most entry)
+// or, failing that, X-Real-IP. It should only be installed behind a
+// load balancer or reverse proxy trusted to set these headers honestly.
+func RealIP(next madhatter.Handler) madhatter.Handler {
+	return madhatter.HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			if ip := strings.TrimSpace(strings.Split(fwd, ",")[0]); ip != "" {
+				r.RemoteAddr = ip
+			}
+		} else if ip := r.Header.Get("X-Real-IP"); ip != "" {
+			r.RemoteAddr = ip
+		}
+
+		next.ServeHTTP(ctx, w, r)
+	})
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"context"
+
+	"github.com/docbobo/madhatter"
+)
+
+// PanicHandler is invoked by RecovererWithHandler once a downstream panic
+// has been recovered, with the panic value and the stack trace captured at
+// the point of recovery. It is responsible for writing a response to w.
+type PanicHandler func(ctx context.Context, w http.ResponseWriter, r *http.Request, rec interface{}, stack []byte)
+
+// defaultPanicHandler logs the panic and writes a plain 500 response.
+func defaultPanicHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, rec interface{}, stack []byte) {
+	log.Printf("panic recovered: %v\n%s", rec, stack)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// Recoverer is a madhatter.Constructor that recovers panics from downstream
+// Handlers, converting them into a 500 response instead of crashing the
+// server. It is RecovererWithHandler(defaultPanicHandler).
+func Recoverer(next madhatter.Handler) madhatter.Handler {
+	return RecovererWithHandler(defaultPanicHandler)(next)
+}
+
+// RecovererWithHandler is like Recoverer, but calls onPanic with the
+// recovered panic value and its stack trace instead of always writing a
+// plain 500, so callers can log or render the panic however they like.
+// onPanic runs synchronously, after the downstream Handler has unwound but
+// before the request completes, so it is the only place the panic value and
+// stack are observable -- by the time Recoverer's defer returns, the
+// response has already been written and there is no ctx a later Handler
+// could read them back from.
+func RecovererWithHandler(onPanic PanicHandler) madhatter.Constructor {
+	return func(next madhatter.Handler) madhatter.Handler {
+		return madhatter.HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					onPanic(ctx, w, r, rec, debug.Stack())
+				}
+			}()
+
+			next.ServeHTTP(ctx, w, r)
+		})
+	}
+}
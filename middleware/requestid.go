@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"context"
+
+	"github.com/docbobo/madhatter"
+)
+
+// requestIDKey is the context key under which RequestID stores the request
+// ID, retrievable via RequestIDFromContext.
+type requestIDKey struct{}
+
+// RequestIDHeader is the header RequestID reads an inbound request ID from
+// and writes the (possibly generated) one to.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDFromContext returns the request ID stashed by RequestID, or "" if
+// none was set.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID is a madhatter.Constructor that propagates the X-Request-ID
+// header if the incoming request carries one, or generates a random one
+// otherwise. The ID is stashed in the context.Context passed downstream
+// (retrievable via RequestIDFromContext) and echoed back on the response.
+func RequestID(next madhatter.Handler) madhatter.Handler {
+	return madhatter.HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		ctx = context.WithValue(ctx, requestIDKey{}, id)
+
+		next.ServeHTTP(ctx, w, r)
+	})
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
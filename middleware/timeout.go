@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"context"
+
+	"github.com/docbobo/madhatter"
+)
+
+// Timeout returns a madhatter.Constructor that derives a context.Context
+// bound by d via context.WithTimeout and passes it to the next Handler. If d
+// elapses before the downstream Handler returns, the Handler observes
+// ctx.Done() (via ctx.Err() == context.DeadlineExceeded) but Timeout itself
+// does not abort the in-flight call -- downstream Handlers and Constructors
+// that honor ctx.Done() are expected to return early.
+func Timeout(d time.Duration) madhatter.Constructor {
+	return func(next madhatter.Handler) madhatter.Handler {
+		return madhatter.HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			next.ServeHTTP(ctx, w, r)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import "net/http"
+
+// statusWriter wraps an http.ResponseWriter, capturing the status code and
+// byte count written so later middleware (Logger, in particular) can
+// observe them without the handler having to report them itself.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func wrapWriter(w http.ResponseWriter) *statusWriter {
+	return &statusWriter{ResponseWriter: w}
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
@@ -0,0 +1,218 @@
+package madhatter
+
+import (
+	"net/http"
+	"strings"
+
+	"context"
+)
+
+// paramsKey is the context key under which route parameters parsed from a
+// `:param`/`*` path pattern are stored.
+type paramsKey struct{}
+
+// Params returns the route parameters matched for the current request, as
+// populated by Router/Group dispatch. It returns nil if ctx carries none.
+func Params(ctx context.Context) map[string]string {
+	params, _ := ctx.Value(paramsKey{}).(map[string]string)
+	return params
+}
+
+// route is a single registered method+pattern pair. An empty method (as set
+// by Mount) matches any request method.
+type route struct {
+	method  string
+	pattern string
+	segs    []string
+	chain   Chain
+	handler Handler
+}
+
+// Router is a Group rooted at "/", the entry point for building up a tree of
+// route groups on top of Chain. It satisfies http.Handler so it can be
+// passed directly to http.ListenAndServe.
+//
+// Router deliberately does not embed *Group: an embedded field of that name
+// would shadow a promoted Group method, breaking r.Group(...). Router's
+// methods forward to root instead.
+type Router struct {
+	root *Group
+}
+
+// NewRouter creates a Router with no middleware installed. Use Group to
+// attach per-prefix middleware and Handle to register routes.
+func NewRouter() *Router {
+	return &Router{
+		root: &Group{
+			prefix: "",
+			chain:  New(),
+			routes: &[]*route{},
+		},
+	}
+}
+
+// Group creates a child Group of the Router's root, as documented on
+// (*Group).Group.
+func (rt *Router) Group(prefix string, constructors ...Constructor) *Group {
+	return rt.root.Group(prefix, constructors...)
+}
+
+// Handle registers handler on the Router's root Group, as documented on
+// (*Group).Handle.
+func (rt *Router) Handle(method, pattern string, handler Handler) {
+	rt.root.Handle(method, pattern, handler)
+}
+
+// HandleFunc registers fn on the Router's root Group, as documented on
+// (*Group).HandleFunc.
+func (rt *Router) HandleFunc(method, pattern string, fn HandlerFunc) {
+	rt.root.HandleFunc(method, pattern, fn)
+}
+
+// Mount registers h on the Router's root Group, as documented on
+// (*Group).Mount.
+func (rt *Router) Mount(prefix string, h http.Handler) {
+	rt.root.Mount(prefix, h)
+}
+
+// ServeHTTP dispatches req to the route registered for its method and path,
+// running the matching Chain of constructors first. Unmatched requests fall
+// through to http.NotFound.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, rte := range *rt.root.routes {
+		if rte.method != "" && rte.method != r.Method {
+			continue
+		}
+		if params, ok := matchPattern(rte.segs, r.URL.Path); ok {
+			handler := rte.handler
+			if len(params) > 0 {
+				r = r.WithContext(contextWithParams(r.Context(), params))
+				handler = withParams(handler, params)
+			}
+			rte.chain.Then(handler).ServeHTTP(w, r)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// Group is a named subtree of routes sharing a path prefix and a Chain of
+// middleware Constructors, modeled on noodle's Wok and go-chi's Router.
+type Group struct {
+	prefix string
+	chain  Chain
+	routes *[]*route
+}
+
+// Group creates a child Group whose prefix is joined to the parent's and
+// whose Chain is the parent's Chain appended with constructors. Middleware
+// added to the child never applies to the parent's routes; routes registered
+// on the child go into the route table the whole Router shares.
+func (g *Group) Group(prefix string, constructors ...Constructor) *Group {
+	return &Group{
+		prefix: joinPrefix(g.prefix, prefix),
+		chain:  g.chain.Append(constructors...),
+		routes: g.routes,
+	}
+}
+
+// Handle registers handler for method and pattern (relative to the Group's
+// prefix), to be run behind the Group's Chain. pattern may contain
+// `:param` segments and a single trailing `*` wildcard; both are populated
+// into the request's context.Context and retrievable via Params.
+func (g *Group) Handle(method, pattern string, handler Handler) {
+	full := joinPrefix(g.prefix, pattern)
+	*g.routes = append(*g.routes, &route{
+		method:  method,
+		pattern: full,
+		segs:    strings.Split(strings.Trim(full, "/"), "/"),
+		chain:   g.chain,
+		handler: handler,
+	})
+}
+
+// HandleFunc is a convenience wrapper for Handle that accepts a HandlerFunc.
+func (g *Group) HandleFunc(method, pattern string, fn HandlerFunc) {
+	g.Handle(method, pattern, fn)
+}
+
+// Mount registers h to handle every method for every path under prefix,
+// behind the Group's Chain, for embedding third-party http.Handlers (e.g.
+// pprof, a reverse proxy, a file server) without adapting them route by
+// route.
+func (g *Group) Mount(prefix string, h http.Handler) {
+	full := joinPrefix(g.prefix, prefix)
+	pattern := strings.TrimRight(full, "/") + "/*"
+	mounted := adaptFinal(h)
+
+	*g.routes = append(*g.routes, &route{
+		method:  "",
+		pattern: pattern,
+		segs:    strings.Split(strings.Trim(pattern, "/"), "/"),
+		chain:   g.chain,
+		handler: mounted,
+	})
+}
+
+func joinPrefix(base, prefix string) string {
+	base = strings.TrimRight(base, "/")
+	prefix = "/" + strings.Trim(prefix, "/")
+	if prefix == "/" {
+		return base + "/"
+	}
+	return base + prefix
+}
+
+// matchPattern matches segs (a pattern split on "/", possibly containing
+// `:name` params and a trailing "*" wildcard) against path, returning the
+// captured params on success.
+func matchPattern(segs []string, path string) (map[string]string, bool) {
+	pathSegs := strings.Split(strings.Trim(path, "/"), "/")
+
+	var params map[string]string
+	for i, seg := range segs {
+		if seg == "*" {
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params["*"] = strings.Join(pathSegs[i:], "/")
+			return params, true
+		}
+
+		if i >= len(pathSegs) {
+			return nil, false
+		}
+
+		if strings.HasPrefix(seg, ":") {
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[seg[1:]] = pathSegs[i]
+			continue
+		}
+
+		if seg != pathSegs[i] {
+			return nil, false
+		}
+	}
+
+	if len(pathSegs) != len(segs) {
+		return nil, false
+	}
+
+	return params, true
+}
+
+func contextWithParams(ctx context.Context, params map[string]string) context.Context {
+	return context.WithValue(ctx, paramsKey{}, params)
+}
+
+// withParams wraps h so that params are injected into its Handler-level
+// ctx. createRootHandler derives that ctx from context.Background() rather
+// than the *http.Request, so dispatch must inject params here rather than
+// relying solely on the request's context.Context.
+func withParams(h Handler, params map[string]string) Handler {
+	return HandlerFunc(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(contextWithParams(ctx, params), w, r)
+	})
+}
@@ -0,0 +1,91 @@
+package madhatter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"context"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterDispatchesByMethodAndPath(t *testing.T) {
+	r := NewRouter()
+	r.Handle("GET", "/users", HandlerFunc(func(ctx context.Context, w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("list\n"))
+	}))
+	r.Handle("POST", "/users", HandlerFunc(func(ctx context.Context, w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("create\n"))
+	}))
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/users", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "create\n", w.Body.String())
+}
+
+func TestRouterPopulatesPathParams(t *testing.T) {
+	r := NewRouter()
+	r.Handle("GET", "/users/:id", HandlerFunc(func(ctx context.Context, w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(Params(ctx)["id"]))
+	}))
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/users/42", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "42", w.Body.String())
+}
+
+func TestGroupAppliesSharedMiddlewareAndPrefix(t *testing.T) {
+	r := NewRouter()
+	api := r.Group("/api", tagMiddleware("auth\n"))
+	api.Handle("GET", "/users", testApp)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/users", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "auth\napp\n", w.Body.String())
+}
+
+func TestMountDispatchesAnyMethodUnderPrefix(t *testing.T) {
+	r := NewRouter()
+	r.Mount("/debug", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("mounted:" + req.URL.Path))
+	}))
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/debug/pprof", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "mounted:/debug/pprof", w.Body.String())
+}
+
+func TestRouterReturnsNotFoundForUnmatchedRoute(t *testing.T) {
+	r := NewRouter()
+	r.Handle("GET", "/users", testApp)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/missing", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,48 @@
+package tcp
+
+// Chain acts as a list of Handlers.
+// Chain is effectively immutable: once created, it will always hold the
+// same set of constructors in the same order.
+type Chain struct {
+	constructors []Constructor
+}
+
+// New creates a new Chain, memorizing the given list of middleware
+// constructors. Constructors are not called until Then() is invoked.
+func New(constructors ...Constructor) Chain {
+	var c Chain
+	c.constructors = append(c.constructors, constructors...)
+	return c
+}
+
+// Append extends a Chain, adding the specified constructors at the end of
+// the Chain. Append returns a new chain, leaving the original one untouched.
+func (c Chain) Append(constructors ...Constructor) Chain {
+	newCons := make([]Constructor, len(c.constructors)+len(constructors))
+	copy(newCons, c.constructors)
+	copy(newCons[len(c.constructors):], constructors)
+
+	return New(newCons...)
+}
+
+// Then chains the middleware and returns the final Handler:
+//     New(m1, m2, m3).Then(h)
+// is equivalent to:
+//     m1(m2(m3(h)))
+//
+// Then short-circuits on the first constructor error and returns it to the
+// caller, so a misconfigured middleware (e.g. a TLS config that doesn't
+// parse) fails at build time rather than at accept time.
+func (c Chain) Then(h Handler) (Handler, error) {
+	final := h
+
+	for i := len(c.constructors) - 1; i >= 0; i-- {
+		var err error
+		final, err = c.constructors[i](final)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return final, nil
+}
@@ -0,0 +1,58 @@
+package tcp
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"context"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tagConstructor(tag *[]string, name string) Constructor {
+	return func(h Handler) (Handler, error) {
+		return HandlerFunc(func(ctx context.Context, conn WriteCloser) {
+			*tag = append(*tag, name)
+			h.ServeTCP(ctx, conn)
+		}), nil
+	}
+}
+
+func TestThenOrdersHandlersRight(t *testing.T) {
+	var calls []string
+	app := HandlerFunc(func(ctx context.Context, conn WriteCloser) {
+		calls = append(calls, "app")
+	})
+
+	chain := New(tagConstructor(&calls, "m1"), tagConstructor(&calls, "m2"))
+	h, err := chain.Then(app)
+	assert.NoError(t, err)
+
+	h.ServeTCP(context.Background(), nil)
+
+	assert.Equal(t, []string{"m1", "m2", "app"}, calls)
+}
+
+func TestThenShortCircuitsOnConstructorError(t *testing.T) {
+	failErr := errors.New("bad TLS config")
+	failing := func(h Handler) (Handler, error) {
+		return nil, failErr
+	}
+
+	chain := New(failing)
+	h, err := chain.Then(HandlerFunc(func(ctx context.Context, conn WriteCloser) {}))
+
+	assert.Nil(t, h)
+	assert.Equal(t, failErr, err)
+}
+
+func TestAppendRespectsImmutability(t *testing.T) {
+	chain := New(tagConstructor(&[]string{}, "m1"))
+	newChain := chain.Append(tagConstructor(&[]string{}, "m2"))
+
+	assert.Equal(t, 1, len(chain.constructors))
+	assert.Equal(t, 2, len(newChain.constructors))
+}
+
+var _ net.Conn = WriteCloser(nil)
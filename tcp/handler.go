@@ -0,0 +1,45 @@
+// Package tcp mirrors madhatter's HTTP Chain for non-HTTP middleware
+// pipelines, following the traefik TCP chain design: Handlers operate on a
+// WriteCloser instead of an http.ResponseWriter/*http.Request pair, which
+// lets madhatter users compose TCP-level middleware (TLS termination,
+// PROXY protocol parsing, rate limiting) with the same mental model as
+// their HTTP chains.
+package tcp
+
+import (
+	"net"
+
+	"context"
+)
+
+// WriteCloser is a net.Conn that additionally supports half-close, as
+// implemented by *net.TCPConn and *tls.Conn.
+type WriteCloser interface {
+	net.Conn
+
+	// CloseWrite shuts down the writing side of the connection, signaling
+	// to the peer that no more data will be sent, without closing the
+	// reading side.
+	CloseWrite() error
+}
+
+// Handler operates on a single accepted connection, in the same spirit as
+// madhatter.Handler operates on a single HTTP request.
+type Handler interface {
+	ServeTCP(ctx context.Context, conn WriteCloser)
+}
+
+// HandlerFunc is an adapter to allow the use of ordinary functions as
+// Handler.
+type HandlerFunc func(ctx context.Context, conn WriteCloser)
+
+// ServeTCP calls f(ctx, conn).
+func (f HandlerFunc) ServeTCP(ctx context.Context, conn WriteCloser) {
+	f(ctx, conn)
+}
+
+// A Constructor for a piece of TCP middleware. Unlike the HTTP Constructor,
+// it may fail at build time -- e.g. a TLS config that doesn't parse -- so
+// that Chain.Then can surface configuration errors to the caller instead of
+// panicking at accept time.
+type Constructor func(Handler) (Handler, error)
@@ -0,0 +1,40 @@
+package tcp
+
+import (
+	"net"
+
+	"context"
+)
+
+// Serve accepts connections from l and dispatches each to h on its own
+// goroutine, until l.Accept returns an error (typically because l was
+// closed). For every accepted connection, Serve derives a cancelable
+// context.Context bound to that connection's lifetime -- wired to the
+// connection, not shared across connections -- and cancels it once
+// h.ServeTCP returns. Accepted connections that do not implement WriteCloser
+// are closed without being dispatched.
+func Serve(l net.Listener, h Handler) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		wc, ok := conn.(WriteCloser)
+		if !ok {
+			conn.Close()
+			continue
+		}
+
+		go serveConn(wc, h)
+	}
+}
+
+func serveConn(conn WriteCloser, h Handler) {
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h.ServeTCP(ctx, conn)
+}